@@ -1,189 +1,231 @@
 package main
 
 import (
-	"errors"
 	"context"
+	"errors"
+	"flag"
 	"fmt"
-	"log"
+	"log/slog"
 	"os"
-	"path/filepath"
 	"strconv"
+	"time"
 
-	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/session"
-	"github.com/aws/aws-sdk-go/service/s3/s3manager"
 	vault "github.com/hashicorp/vault/api"
-	auth "github.com/hashicorp/vault/api/auth/aws"
+
+	"github.com/mitodl/vault-raft-backup/pkg/logging"
+	"github.com/mitodl/vault-raft-backup/pkg/metrics"
+	"github.com/mitodl/vault-raft-backup/pkg/pipeline"
+	"github.com/mitodl/vault-raft-backup/pkg/restore"
+	"github.com/mitodl/vault-raft-backup/pkg/snapshot"
+	"github.com/mitodl/vault-raft-backup/pkg/storage"
+	"github.com/mitodl/vault-raft-backup/pkg/vaultauth"
 )
 
 // VaultConfig is for vault interface
 type VaultConfig struct {
 	vaultAddr    string
-	token        string
 	snapshotPath string
 	insecure     bool
 }
 
-// AWSConfig is for aws interaction
-type AWSConfig struct {
-	s3Bucket string
-	s3Prefix string
-	s3Region string
+func main() {
+	slog.SetDefault(logging.New())
+
+	if len(os.Args) > 1 && os.Args[1] == "restore" {
+		if err := runRestore(os.Args[2:]); err != nil {
+			slog.Error(err.Error())
+			os.Exit(1)
+		}
+		return
+	}
+
+	runBackup()
 }
 
-func main() {
-	// initialize vaultConfig and awsConfig
+// runBackup is the default command: take (and optionally schedule) Vault
+// Raft snapshots and upload them to the configured destinations.
+func runBackup() {
+	once := flag.Bool("once", false, "take a single snapshot and exit instead of running the scheduled daemon")
+	flag.Parse()
+
+	// initialize vaultConfig and snapshotConfig
 	insecure, err := strconv.ParseBool(os.Getenv("VAULT_SKIP_VERIFY"))
 	if err != nil {
-		log.Fatalln("Invalid boolean value for VAULT_SKIP_VERIFY")
+		slog.Error("Invalid boolean value for VAULT_SKIP_VERIFY")
+		os.Exit(1)
 	}
 	vaultConfig := VaultConfig{
 		vaultAddr:    os.Getenv("VAULT_ADDR"),
-		token:        os.Getenv("VAULT_TOKEN"),
 		snapshotPath: os.Getenv("VAULT_SNAPSHOT_PATH"),
 		insecure:     insecure,
 	}
-	awsConfig := AWSConfig{
-		s3Bucket: os.Getenv("S3_BUCKET"),
-		s3Prefix: os.Getenv("S3_PREFIX"),
-		s3Region: os.Getenv("AWS_REGION"),
+	snapshotConfig, err := snapshotConfigFromEnv()
+	if err != nil {
+		slog.Error(err.Error())
+		os.Exit(1)
 	}
 
-	// initialize and configure client
-  vaultClient, err := vaultClientConfig(&vaultConfig)
+	ctx := context.Background()
+
+	destinations, err := storage.LoadFromEnv(ctx)
 	if err != nil {
-		log.Fatalln("Vault client initialization and configuration failed")
+		slog.Error(err.Error())
+		os.Exit(1)
 	}
 
-	// vault raft snapshot
-	snapshotFile, err := vaultRaftSnapshot(vaultClient, vaultConfig.snapshotPath)
+	snapshotPipeline, err := pipeline.LoadFromEnv()
 	if err != nil {
-		log.Fatalln("Vault Raft Snapshot failed")
+		slog.Error(err.Error())
+		os.Exit(1)
 	}
 
-	// initialize awsConfig
-	uploadResult, err := snapshotS3Upload(&awsConfig, snapshotFile.Name())
+	// initialize and configure client
+	vaultClient, authMethod, authInfo, err := vaultClientConfig(ctx, &vaultConfig)
 	if err != nil {
-		log.Fatalln("S3 upload failed")
+		slog.Error("Vault client initialization and configuration failed", "error", err)
+		os.Exit(1)
 	}
 
-	// output info
-	fmt.Printf("Vault Raft snapshot uploaded to, %s\n", aws.StringValue(&uploadResult.Location))
-}
+	daemon := &snapshot.Daemon{
+		Vault:        vaultClient,
+		SnapshotPath: vaultConfig.snapshotPath,
+		Destinations: destinations,
+		Pipeline:     snapshotPipeline,
+		Config:       snapshotConfig,
+	}
 
-// vault client configuration
-func vaultClientConfig(config *VaultConfig) (*vault.Client, error) {
-	// initialize config
-	vaultConfig := &vault.Config{Address: config.vaultAddr}
-	err := vaultConfig.ConfigureTLS(&vault.TLSConfig{Insecure: config.insecure})
-	if err != nil {
-		fmt.Println("Vault TLS configuration failed to initialize")
-		fmt.Println(err)
-		return nil, err
+	// Preserve the tool's original one-shot cronjob behavior: without
+	// SNAPSHOT_FREQUENCY there's nothing to schedule against, so run a
+	// single cycle even if --once wasn't passed explicitly.
+	runOnce := *once
+	if snapshotConfig.Frequency <= 0 {
+		runOnce = true
 	}
 
-	// initialize client
-	client, err := vault.NewClient(vaultConfig)
-	if err != nil {
-		fmt.Println("Vault client failed to initialize")
-		fmt.Println(err)
-		return nil, err
+	if !runOnce {
+		vaultauth.StartRenewal(ctx, vaultClient, authMethod, authInfo)
+
+		if addr := os.Getenv("METRICS_ADDR"); addr != "" {
+			metricsServer := metrics.NewServer(addr)
+			go func() {
+				if err := metricsServer.ListenAndServe(); err != nil {
+					slog.Error("metrics server stopped", "error", err)
+				}
+			}()
+		}
 	}
 
-	// determine authentication method
-	if config.token == "aws-iam" {
-		// authenticate with aws iam
-		awsAuth, err := auth.NewAWSAuth(auth.WithIAMAuth())
+	if err := daemon.Run(ctx, runOnce); err != nil {
+		slog.Error(err.Error())
+		os.Exit(1)
+	}
+}
+
+// snapshotConfigFromEnv builds the scheduled snapshot configuration from
+// environment variables. SNAPSHOT_FREQUENCY is parsed as a time.Duration
+// (e.g. "1h"); it is only required when running in daemon mode.
+func snapshotConfigFromEnv() (snapshot.Config, error) {
+	var frequency time.Duration
+	if raw := os.Getenv("SNAPSHOT_FREQUENCY"); raw != "" {
+		var err error
+		frequency, err = time.ParseDuration(raw)
 		if err != nil {
-			return nil, errors.New("Unable to initialize AWS IAM authentication")
+			return snapshot.Config{}, errors.New("Invalid duration value for SNAPSHOT_FREQUENCY")
 		}
+	}
 
-		authInfo, err := client.Auth().Login(context.TODO(), awsAuth)
+	retention := 0
+	if raw := os.Getenv("SNAPSHOT_RETENTION"); raw != "" {
+		var err error
+		retention, err = strconv.Atoi(raw)
 		if err != nil {
-			return nil, errors.New("Unable to login to AWS IAM auth method")
+			return snapshot.Config{}, errors.New("Invalid integer value for SNAPSHOT_RETENTION")
 		}
-		if authInfo == nil {
-			return nil, errors.New("No auth info was returned after login")
-		}
-	} else {
-		// authenticate with token
-		if len(config.token) != 26 {
-			return nil, errors.New("The Vault token is invalid")
-		}
-		client.SetToken(config.token)
 	}
 
-	// return vault client interface
-  return client, nil
+	return snapshot.Config{
+		Frequency:       frequency,
+		Retention:       retention,
+		TimestampFormat: os.Getenv("SNAPSHOT_TIMESTAMP_FORMAT"),
+		NameSuffix:      os.Getenv("SNAPSHOT_NAME_SUFFIX"),
+	}, nil
 }
 
-// vault raft snapshot creation
-func vaultRaftSnapshot(client *vault.Client, snapshotPath string) (*os.File, error) {
-	// prepare snaptshot file
-	snapshotFile, err := os.OpenFile(snapshotPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
-	if err != nil {
-		fmt.Println("snapshot file at " + snapshotPath + " could not be created")
-		fmt.Println(err)
-		return nil, err
+// runRestore handles the "restore" subcommand: download a snapshot (or the
+// latest one) from S3 and restore it into the target Vault cluster.
+func runRestore(args []string) error {
+	fs := flag.NewFlagSet("restore", flag.ExitOnError)
+	force := fs.Bool("force", false, "restore without prompting for confirmation")
+	fs.Parse(args)
+
+	if fs.NArg() != 1 {
+		return errors.New("usage: vault-raft-backup restore [--force] <s3-key|latest>")
 	}
 
-	// defer snapshot close
-	defer snapshotFileClose(snapshotFile)
+	ctx := context.Background()
 
-	// execute raft snapshot
-	err = client.Sys().RaftSnapshot(snapshotFile)
+	insecure, err := strconv.ParseBool(os.Getenv("VAULT_SKIP_VERIFY"))
 	if err != nil {
-		snapshotFile.Close()
-		fmt.Println("Vault Raft snapshot invocation failed")
-		fmt.Println(err)
-		return nil, err
+		return errors.New("Invalid boolean value for VAULT_SKIP_VERIFY")
+	}
+	vaultConfig := VaultConfig{
+		vaultAddr:    os.Getenv("VAULT_ADDR"),
+		snapshotPath: os.Getenv("VAULT_SNAPSHOT_PATH"),
+		insecure:     insecure,
+	}
+	vaultClient, _, _, err := vaultClientConfig(ctx, &vaultConfig)
+	if err != nil {
+		return fmt.Errorf("Vault client initialization and configuration failed: %w", err)
 	}
 
-	return snapshotFile, nil
-}
-
-// snapshot upload to s3
-func snapshotS3Upload(config *AWSConfig, snapshotPath string) (*s3manager.UploadOutput, error) {
-	// open snapshot and defer closing
-	snapshotFile, err := os.Open(snapshotPath)
+	snapshotPipeline, err := pipeline.LoadFromEnv()
 	if err != nil {
-		fmt.Printf("Failed to open snapshot file %q: %v", snapshotPath, err)
-		return nil, err
+		return err
 	}
-	defer snapshotFileClose(snapshotFile)
 
-	// aws session
-	awsSession := session.Must(session.NewSession(&aws.Config{
-		Region: aws.String(config.s3Region),
-	}))
+	opts := restore.Options{
+		S3: storage.S3Config{
+			Bucket: os.Getenv("S3_BUCKET"),
+			Prefix: os.Getenv("S3_PREFIX"),
+			Region: os.Getenv("AWS_REGION"),
+		},
+		Key:      fs.Arg(0),
+		Force:    *force,
+		Pipeline: snapshotPipeline,
+	}
 
-	// initialize an uploader with the session and default options
-	uploader := s3manager.NewUploader(awsSession)
+	return restore.Restore(ctx, vaultClient, opts)
+}
 
-	// determine vault backup base for s3 key
-	snapshotPathBase := filepath.Base(snapshotPath)
+// vault client configuration. Returns the authenticated client along with
+// the AuthMethod and auth secret used, so callers can keep the token renewed.
+func vaultClientConfig(ctx context.Context, config *VaultConfig) (*vault.Client, vaultauth.AuthMethod, *vault.Secret, error) {
+	// initialize config
+	vaultConfig := &vault.Config{Address: config.vaultAddr}
+	err := vaultConfig.ConfigureTLS(&vault.TLSConfig{Insecure: config.insecure})
+	if err != nil {
+		slog.Error("Vault TLS configuration failed to initialize", "error", err)
+		return nil, nil, nil, err
+	}
 
-	// upload the snapshot to the s3bucket at specified key
-	uploadResult, err := uploader.Upload(&s3manager.UploadInput{
-		Bucket: aws.String(config.s3Bucket),
-		Key:    aws.String(config.s3Prefix + "-" + snapshotPathBase),
-		Body:   snapshotFile,
-	})
+	// initialize client
+	client, err := vault.NewClient(vaultConfig)
 	if err != nil {
-		fmt.Println("Vault backup failed to upload to S3 bucket " + config.s3Bucket)
-		fmt.Println(err)
-		return nil, err
+		slog.Error("Vault client failed to initialize", "error", err)
+		return nil, nil, nil, err
 	}
 
-	return uploadResult, nil
-}
+	// determine and perform authentication
+	method, err := vaultauth.LoadFromEnv()
+	if err != nil {
+		return nil, nil, nil, err
+	}
 
-// close snapshot file
-func snapshotFileClose(snapshotFile *os.File) {
-	// close file
-	err := snapshotFile.Close()
+	authInfo, err := method.Login(ctx, client)
 	if err != nil {
-		fmt.Println("Vault raft snapshot file failed to close")
-		log.Fatalln(err)
+		return nil, nil, nil, err
 	}
+
+	// return vault client interface
+	return client, method, authInfo, nil
 }
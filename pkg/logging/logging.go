@@ -0,0 +1,36 @@
+// Package logging configures the process-wide structured logger.
+package logging
+
+import (
+	"log/slog"
+	"os"
+)
+
+// New builds a slog.Logger from LOG_FORMAT ("text" or "json", defaults to
+// "text") and LOG_LEVEL ("debug", "info", "warn", or "error", defaults to
+// "info").
+func New() *slog.Logger {
+	opts := &slog.HandlerOptions{Level: parseLevel(os.Getenv("LOG_LEVEL"))}
+
+	var handler slog.Handler
+	if os.Getenv("LOG_FORMAT") == "json" {
+		handler = slog.NewJSONHandler(os.Stdout, opts)
+	} else {
+		handler = slog.NewTextHandler(os.Stdout, opts)
+	}
+
+	return slog.New(handler)
+}
+
+func parseLevel(raw string) slog.Level {
+	switch raw {
+	case "debug":
+		return slog.LevelDebug
+	case "warn":
+		return slog.LevelWarn
+	case "error":
+		return slog.LevelError
+	default:
+		return slog.LevelInfo
+	}
+}
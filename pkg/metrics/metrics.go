@@ -0,0 +1,75 @@
+// Package metrics exposes Prometheus metrics and health endpoints for the
+// snapshot daemon, so it can run as a long-lived Kubernetes sidecar rather
+// than a bare cronjob.
+package metrics
+
+import (
+	"sync/atomic"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/promauto"
+)
+
+var (
+	// SnapshotsTotal counts snapshot uploads by result ("success"/"failure") and destination.
+	SnapshotsTotal = promauto.NewCounterVec(prometheus.CounterOpts{
+		Name: "vault_raft_backup_snapshots_total",
+		Help: "Total number of snapshot uploads, labeled by result and destination.",
+	}, []string{"result", "destination"})
+
+	// LastSuccessTimestamp is the unix timestamp of the last fully successful snapshot cycle.
+	LastSuccessTimestamp = promauto.NewGauge(prometheus.GaugeOpts{
+		Name: "vault_raft_backup_last_success_timestamp_seconds",
+		Help: "Unix timestamp of the last fully successful snapshot cycle.",
+	})
+
+	// SnapshotBytes tracks the size of uploaded snapshots.
+	SnapshotBytes = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "vault_raft_backup_snapshot_bytes",
+		Help:    "Size in bytes of uploaded snapshots.",
+		Buckets: prometheus.ExponentialBuckets(1<<20, 2, 10),
+	})
+
+	// SnapshotDuration tracks how long a full snapshot cycle takes.
+	SnapshotDuration = promauto.NewHistogram(prometheus.HistogramOpts{
+		Name:    "vault_raft_backup_duration_seconds",
+		Help:    "Duration of a full snapshot cycle, across all destinations.",
+		Buckets: prometheus.DefBuckets,
+	})
+)
+
+var ready atomic.Bool
+
+// SetReady marks whether the daemon's most recent snapshot cycle succeeded;
+// /readyz reflects this.
+func SetReady(r bool) {
+	ready.Store(r)
+}
+
+// IsReady reports the current readiness state.
+func IsReady() bool {
+	return ready.Load()
+}
+
+// RecordUpload records the outcome of a single destination upload.
+func RecordUpload(destination string, success bool, bytes int) {
+	result := "success"
+	if !success {
+		result = "failure"
+	}
+	SnapshotsTotal.WithLabelValues(result, destination).Inc()
+	if success {
+		SnapshotBytes.Observe(float64(bytes))
+	}
+}
+
+// RecordCycle records the outcome and duration of a full snapshot cycle and
+// updates readiness accordingly.
+func RecordCycle(success bool, duration time.Duration) {
+	SnapshotDuration.Observe(duration.Seconds())
+	if success {
+		LastSuccessTimestamp.Set(float64(time.Now().Unix()))
+	}
+	SetReady(success)
+}
@@ -0,0 +1,68 @@
+package pipeline
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"filippo.io/age"
+)
+
+// AgeEncryptor encrypts snapshots to one or more age recipients, for
+// portable decryption outside of AWS by an operator holding the matching
+// identity file.
+type AgeEncryptor struct {
+	Recipients []age.Recipient
+	Identities []age.Identity
+}
+
+// NewAgeEncryptor parses recipients (age1... public keys) for encryption. If
+// identityFile is non-empty, the age identities (AGE-SECRET-KEY-... lines)
+// in it are also parsed and stored on Identities so the same Pipeline can
+// Unwrap during restore.
+func NewAgeEncryptor(recipients []string, identityFile string) (*AgeEncryptor, error) {
+	parsed := make([]age.Recipient, 0, len(recipients))
+	for _, r := range recipients {
+		recipient, err := age.ParseX25519Recipient(r)
+		if err != nil {
+			return nil, fmt.Errorf("invalid age recipient %q: %w", r, err)
+		}
+		parsed = append(parsed, recipient)
+	}
+
+	encryptor := &AgeEncryptor{Recipients: parsed}
+
+	if identityFile != "" {
+		f, err := os.Open(identityFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open age identity file %s: %w", identityFile, err)
+		}
+		defer f.Close()
+
+		identities, err := age.ParseIdentities(f)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse age identity file %s: %w", identityFile, err)
+		}
+		encryptor.Identities = identities
+	}
+
+	return encryptor, nil
+}
+
+// Wrap implements Encryptor.
+func (e *AgeEncryptor) Wrap(w io.Writer) (io.WriteCloser, error) {
+	encWriter, err := age.Encrypt(w, e.Recipients...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize age encryption: %w", err)
+	}
+	return encWriter, nil
+}
+
+// Unwrap implements Encryptor. e.Identities must be set for restore.
+func (e *AgeEncryptor) Unwrap(r io.Reader) (io.Reader, error) {
+	decrypted, err := age.Decrypt(r, e.Identities...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize age decryption: %w", err)
+	}
+	return decrypted, nil
+}
@@ -0,0 +1,49 @@
+package pipeline
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// LoadFromEnv builds a Pipeline from SNAPSHOT_COMPRESSION ("gzip", "zstd",
+// or unset/"none") and, optionally, exactly one of SNAPSHOT_KMS_KEY_ARN
+// (AWS KMS envelope encryption) or SNAPSHOT_AGE_RECIPIENTS (a comma
+// separated list of age public keys) for encryption. When age is used,
+// SNAPSHOT_AGE_IDENTITY_FILE may also point at a file of age identities
+// (private keys) so the same Pipeline can decrypt snapshots on restore.
+func LoadFromEnv() (Pipeline, error) {
+	var p Pipeline
+
+	switch compression := os.Getenv("SNAPSHOT_COMPRESSION"); compression {
+	case "", "none":
+	case "gzip":
+		p.Compression = GzipCompressor{}
+	case "zstd":
+		p.Compression = ZstdCompressor{}
+	default:
+		return Pipeline{}, fmt.Errorf("unknown SNAPSHOT_COMPRESSION %q", compression)
+	}
+
+	kmsKeyARN := os.Getenv("SNAPSHOT_KMS_KEY_ARN")
+	ageRecipients := os.Getenv("SNAPSHOT_AGE_RECIPIENTS")
+
+	switch {
+	case kmsKeyARN != "" && ageRecipients != "":
+		return Pipeline{}, fmt.Errorf("SNAPSHOT_KMS_KEY_ARN and SNAPSHOT_AGE_RECIPIENTS are mutually exclusive")
+	case kmsKeyARN != "":
+		encryptor, err := NewKMSEncryptor(kmsKeyARN)
+		if err != nil {
+			return Pipeline{}, err
+		}
+		p.Encryption = encryptor
+	case ageRecipients != "":
+		encryptor, err := NewAgeEncryptor(strings.Split(ageRecipients, ","), os.Getenv("SNAPSHOT_AGE_IDENTITY_FILE"))
+		if err != nil {
+			return Pipeline{}, err
+		}
+		p.Encryption = encryptor
+	}
+
+	return p, nil
+}
@@ -0,0 +1,24 @@
+package pipeline
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+)
+
+// GzipCompressor compresses snapshots with gzip.
+type GzipCompressor struct{}
+
+// Wrap implements Compressor.
+func (GzipCompressor) Wrap(w io.Writer) io.WriteCloser {
+	return gzip.NewWriter(w)
+}
+
+// Unwrap implements Compressor.
+func (GzipCompressor) Unwrap(r io.Reader) (io.Reader, error) {
+	reader, err := gzip.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize gzip reader: %w", err)
+	}
+	return reader, nil
+}
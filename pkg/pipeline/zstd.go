@@ -0,0 +1,27 @@
+package pipeline
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+// ZstdCompressor compresses snapshots with zstd.
+type ZstdCompressor struct{}
+
+// Wrap implements Compressor.
+func (ZstdCompressor) Wrap(w io.Writer) io.WriteCloser {
+	// zstd.NewWriter only errors on invalid options, and none are set here.
+	encoder, _ := zstd.NewWriter(w)
+	return encoder
+}
+
+// Unwrap implements Compressor.
+func (ZstdCompressor) Unwrap(r io.Reader) (io.Reader, error) {
+	decoder, err := zstd.NewReader(r)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize zstd reader: %w", err)
+	}
+	return decoder, nil
+}
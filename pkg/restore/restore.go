@@ -0,0 +1,196 @@
+// Package restore implements the counterpart to pkg/snapshot: downloading a
+// previously uploaded Vault Raft snapshot from S3 and restoring it into a
+// live cluster.
+package restore
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+	vault "github.com/hashicorp/vault/api"
+
+	"github.com/mitodl/vault-raft-backup/pkg/pipeline"
+	"github.com/mitodl/vault-raft-backup/pkg/snapshot"
+	"github.com/mitodl/vault-raft-backup/pkg/storage"
+)
+
+// latestKeyword resolves to the newest snapshot under S3.Prefix.
+const latestKeyword = "latest"
+
+// Options configures a restore run.
+type Options struct {
+	S3       storage.S3Config
+	Key      string // S3 object key, or "latest" to resolve the newest snapshot under S3.Prefix
+	Force    bool
+	Pipeline pipeline.Pipeline
+}
+
+// Restore downloads a snapshot from S3, verifies its checksum, and restores
+// it into the Vault cluster backing client.
+func Restore(ctx context.Context, client *vault.Client, opts Options) error {
+	s3Client, err := newS3Client(opts.S3)
+	if err != nil {
+		return err
+	}
+
+	key := opts.Key
+	if key == latestKeyword {
+		key, err = latestKey(ctx, s3Client, opts.S3)
+		if err != nil {
+			return err
+		}
+	}
+
+	tempFile, err := os.CreateTemp("", "vault-raft-restore-*.snap")
+	if err != nil {
+		return fmt.Errorf("failed to create temp file for restore: %w", err)
+	}
+	defer os.Remove(tempFile.Name())
+	defer tempFile.Close()
+
+	downloader := s3manager.NewDownloaderWithClient(s3Client)
+	if _, err := downloader.DownloadWithContext(ctx, tempFile, &s3.GetObjectInput{
+		Bucket: aws.String(opts.S3.Bucket),
+		Key:    aws.String(key),
+	}); err != nil {
+		return fmt.Errorf("failed to download snapshot %s: %w", key, err)
+	}
+
+	if err := verifyChecksum(ctx, s3Client, opts.S3.Bucket, key, tempFile.Name()); err != nil {
+		return err
+	}
+
+	if !opts.Force && !confirmRestore(key) {
+		return fmt.Errorf("restore of %s aborted", key)
+	}
+
+	snapshotFile, err := os.Open(tempFile.Name())
+	if err != nil {
+		return fmt.Errorf("failed to reopen downloaded snapshot: %w", err)
+	}
+	defer snapshotFile.Close()
+
+	var snapshotReader io.Reader = snapshotFile
+	if opts.Pipeline.Compression != nil || opts.Pipeline.Encryption != nil {
+		snapshotReader, err = opts.Pipeline.Unwrap(snapshotFile)
+		if err != nil {
+			return fmt.Errorf("failed to decode snapshot %s: %w", key, err)
+		}
+	}
+
+	if err := client.Sys().RaftSnapshotRestore(snapshotReader, opts.Force); err != nil {
+		return fmt.Errorf("failed to restore Vault Raft snapshot %s: %w", key, err)
+	}
+
+	return nil
+}
+
+// newS3Client builds an S3 client from config, supporting S3-compatible
+// endpoints (e.g. MinIO) the same way pkg/storage does.
+func newS3Client(config storage.S3Config) (*s3.S3, error) {
+	awsConfig := &aws.Config{Region: aws.String(config.Region)}
+	if config.Endpoint != "" {
+		awsConfig.Endpoint = aws.String(config.Endpoint)
+		awsConfig.S3ForcePathStyle = aws.Bool(config.ForcePathStyle)
+	}
+	if config.AccessKey != "" || config.SecretKey != "" {
+		awsConfig.Credentials = credentials.NewStaticCredentials(config.AccessKey, config.SecretKey, "")
+	}
+
+	awsSession, err := session.NewSession(awsConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize S3 session: %w", err)
+	}
+
+	return s3.New(awsSession), nil
+}
+
+// latestKey finds the most recently modified snapshot object under
+// config.Prefix, ignoring checksum sidecars.
+func latestKey(ctx context.Context, client *s3.S3, config storage.S3Config) (string, error) {
+	var objects []*s3.Object
+	err := client.ListObjectsV2PagesWithContext(ctx, &s3.ListObjectsV2Input{
+		Bucket: aws.String(config.Bucket),
+		Prefix: aws.String(config.Prefix),
+	}, func(page *s3.ListObjectsV2Output, lastPage bool) bool {
+		for _, object := range page.Contents {
+			if !strings.HasSuffix(aws.StringValue(object.Key), snapshot.ChecksumSuffix) {
+				objects = append(objects, object)
+			}
+		}
+		return true
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to list snapshots under %s: %w", config.Prefix, err)
+	}
+	if len(objects) == 0 {
+		return "", fmt.Errorf("no snapshots found under %s", config.Prefix)
+	}
+
+	sort.Slice(objects, func(i, j int) bool { return objects[i].LastModified.After(*objects[j].LastModified) })
+	return aws.StringValue(objects[0].Key), nil
+}
+
+// verifyChecksum downloads the sidecar checksum object written at backup
+// time and compares it against the SHA256 of the downloaded snapshot.
+func verifyChecksum(ctx context.Context, client *s3.S3, bucket string, key string, localPath string) error {
+	checksumObject, err := client.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(bucket),
+		Key:    aws.String(key + snapshot.ChecksumSuffix),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to fetch checksum sidecar for %s: %w", key, err)
+	}
+	defer checksumObject.Body.Close()
+
+	var checksumBuf bytes.Buffer
+	if _, err := io.Copy(&checksumBuf, checksumObject.Body); err != nil {
+		return fmt.Errorf("failed to read checksum sidecar for %s: %w", key, err)
+	}
+	expected := strings.TrimSpace(checksumBuf.String())
+
+	file, err := os.Open(localPath)
+	if err != nil {
+		return fmt.Errorf("failed to open downloaded snapshot for checksum verification: %w", err)
+	}
+	defer file.Close()
+
+	hasher := sha256.New()
+	if _, err := io.Copy(hasher, file); err != nil {
+		return fmt.Errorf("failed to hash downloaded snapshot: %w", err)
+	}
+	actual := hex.EncodeToString(hasher.Sum(nil))
+
+	if actual != expected {
+		return fmt.Errorf("checksum mismatch for %s: expected %s, got %s", key, expected, actual)
+	}
+
+	return nil
+}
+
+// confirmRestore prompts the operator to confirm a destructive restore.
+func confirmRestore(key string) bool {
+	fmt.Printf("This will overwrite the cluster's Raft data with snapshot %s. Continue? [y/N]: ", key)
+
+	reader := bufio.NewReader(os.Stdin)
+	response, err := reader.ReadString('\n')
+	if err != nil {
+		return false
+	}
+
+	response = strings.ToLower(strings.TrimSpace(response))
+	return response == "y" || response == "yes"
+}
@@ -0,0 +1,348 @@
+// Package snapshot implements the scheduled Vault Raft snapshot cycle: take a
+// snapshot, fan it out to every configured storage destination, and prune old
+// snapshots beyond the configured retention.
+package snapshot
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	vault "github.com/hashicorp/vault/api"
+
+	"github.com/mitodl/vault-raft-backup/pkg/metrics"
+	"github.com/mitodl/vault-raft-backup/pkg/pipeline"
+	"github.com/mitodl/vault-raft-backup/pkg/storage"
+)
+
+// ChecksumSuffix is appended to a snapshot's name to form the sidecar
+// object that holds its hex-encoded SHA256, which restore verifies against.
+const ChecksumSuffix = ".sha256"
+
+// defaultTimestampFormat is used when Config.TimestampFormat is unset.
+const defaultTimestampFormat = "20060102-150405"
+
+// maxAttempts bounds the exponential backoff retry of a single cycle.
+const maxAttempts = 5
+
+// Config controls the scheduled snapshot cycle.
+type Config struct {
+	Frequency       time.Duration
+	Retention       int
+	TimestampFormat string
+	NameSuffix      string
+}
+
+// Daemon runs the scheduled snapshot/upload/retention cycle against a Vault cluster.
+type Daemon struct {
+	Vault        *vault.Client
+	SnapshotPath string
+	Destinations []storage.SnapshotUploader
+	Pipeline     pipeline.Pipeline
+	Config       Config
+
+	mu      sync.Mutex
+	running bool
+}
+
+// Run executes the snapshot cycle. When once is true it runs a single cycle
+// and returns, matching the tool's original one-shot behavior. Otherwise it
+// loops on Config.Frequency until ctx is canceled.
+func (d *Daemon) Run(ctx context.Context, once bool) error {
+	if once {
+		return d.cycleWithRetry(ctx)
+	}
+
+	if d.Config.Frequency <= 0 {
+		return errors.New("snapshot: Config.Frequency must be positive to run in daemon mode")
+	}
+
+	ticker := time.NewTicker(d.Config.Frequency)
+	defer ticker.Stop()
+
+	for {
+		d.tick(ctx)
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// tick runs a single cycle, skipping it entirely if the previous cycle is
+// still in flight so at most one snapshot runs at a time.
+func (d *Daemon) tick(ctx context.Context) {
+	d.mu.Lock()
+	if d.running {
+		d.mu.Unlock()
+		slog.Warn("previous snapshot cycle still running, skipping this tick")
+		return
+	}
+	d.running = true
+	d.mu.Unlock()
+
+	defer func() {
+		d.mu.Lock()
+		d.running = false
+		d.mu.Unlock()
+	}()
+
+	if err := d.cycleWithRetry(ctx); err != nil {
+		slog.Error("snapshot cycle failed", "error", err)
+	}
+}
+
+// cycleWithRetry retries a failed cycle with exponential backoff, up to
+// maxAttempts, and records the outcome in metrics.
+func (d *Daemon) cycleWithRetry(ctx context.Context) error {
+	start := time.Now()
+	backoff := time.Second
+
+	var err error
+	for attempt := 1; attempt <= maxAttempts; attempt++ {
+		if err = d.cycle(ctx); err == nil {
+			metrics.RecordCycle(true, time.Since(start))
+			return nil
+		}
+
+		slog.Error("snapshot cycle attempt failed", "attempt", attempt, "max_attempts", maxAttempts, "error", err)
+		if attempt == maxAttempts {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			metrics.RecordCycle(false, time.Since(start))
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+	}
+
+	metrics.RecordCycle(false, time.Since(start))
+	return fmt.Errorf("snapshot cycle failed after %d attempts: %w", maxAttempts, err)
+}
+
+// cycle takes a single uniquely-named snapshot, uploads it to every
+// configured destination, and prunes old snapshots beyond the configured
+// retention.
+func (d *Daemon) cycle(ctx context.Context) error {
+	name := d.snapshotName()
+
+	snapshotFile, err := vaultRaftSnapshot(d.Vault, d.SnapshotPath)
+	if err != nil {
+		return err
+	}
+
+	if err := d.uploadToAll(ctx, name, snapshotFile.Name()); err != nil {
+		return err
+	}
+
+	if d.Config.Retention > 0 {
+		if err := d.enforceRetention(ctx); err != nil {
+			return fmt.Errorf("retention cleanup failed: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// uploadToAll fans the snapshot out to every destination, aggregating
+// per-destination errors so a single backend failure doesn't block the
+// others.
+func (d *Daemon) uploadToAll(ctx context.Context, name string, snapshotPath string) error {
+	var wg sync.WaitGroup
+	errs := make([]error, len(d.Destinations))
+
+	for i, destination := range d.Destinations {
+		wg.Add(1)
+		go func(i int, destination storage.SnapshotUploader) {
+			defer wg.Done()
+
+			reader, closeReader, err := d.openForUpload(snapshotPath)
+			if err != nil {
+				errs[i] = err
+				metrics.RecordUpload(destination.Name(), false, 0)
+				return
+			}
+			defer closeReader()
+
+			hasher := sha256.New()
+			counter := &byteCounter{}
+			location, err := destination.Upload(ctx, name, io.TeeReader(reader, io.MultiWriter(hasher, counter)))
+			if err != nil {
+				errs[i] = err
+				metrics.RecordUpload(destination.Name(), false, 0)
+				return
+			}
+			metrics.RecordUpload(destination.Name(), true, counter.n)
+			slog.Info("snapshot uploaded", "destination", destination.Name(), "location", location)
+
+			// A sidecar-only failure doesn't invalidate the snapshot object
+			// that's already landed on this destination, so it's logged
+			// rather than folded into errs: failing the whole cycle here
+			// would make cycleWithRetry re-run vaultRaftSnapshot and
+			// re-upload to every destination, including the ones that
+			// already succeeded.
+			checksum := hex.EncodeToString(hasher.Sum(nil))
+			if _, err := destination.Upload(ctx, name+ChecksumSuffix, strings.NewReader(checksum)); err != nil {
+				slog.Warn("failed to upload checksum sidecar", "destination", destination.Name(), "name", name, "error", err)
+			}
+		}(i, destination)
+	}
+	wg.Wait()
+
+	return joinErrors(errs)
+}
+
+// byteCounter is an io.Writer that only tracks the number of bytes written to it.
+type byteCounter struct{ n int }
+
+func (c *byteCounter) Write(p []byte) (int, error) {
+	c.n += len(p)
+	return len(p), nil
+}
+
+// openForUpload opens the local snapshot file and, if a pipeline is
+// configured, wraps it with compression/encryption via an io.Pipe so
+// destinations never see the plaintext snapshot.
+func (d *Daemon) openForUpload(snapshotPath string) (io.Reader, func(), error) {
+	snapshotFile, err := os.Open(snapshotPath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to open snapshot file %q: %w", snapshotPath, err)
+	}
+
+	if d.Pipeline.Compression == nil && d.Pipeline.Encryption == nil {
+		return snapshotFile, func() { snapshotFileClose(snapshotFile) }, nil
+	}
+
+	pipeReader, pipeWriter := io.Pipe()
+	go func() {
+		defer snapshotFileClose(snapshotFile)
+
+		wrapped, err := d.Pipeline.Wrap(pipeWriter)
+		if err != nil {
+			pipeWriter.CloseWithError(err)
+			return
+		}
+		if _, err := io.Copy(wrapped, snapshotFile); err != nil {
+			wrapped.Close()
+			pipeWriter.CloseWithError(err)
+			return
+		}
+		pipeWriter.CloseWithError(wrapped.Close())
+	}()
+
+	return pipeReader, func() { pipeReader.Close() }, nil
+}
+
+// enforceRetention prunes old snapshots on every destination that supports
+// enumeration and deletion. Retention counts real snapshots only: List
+// returns both a snapshot's object and its .sha256 sidecar (uploaded via the
+// same Upload path in uploadToAll), so sidecars are filtered out before
+// Config.Retention is applied, and each pruned snapshot's paired sidecar is
+// deleted alongside it.
+func (d *Daemon) enforceRetention(ctx context.Context) error {
+	errs := make([]error, len(d.Destinations))
+
+	for i, destination := range d.Destinations {
+		retainer, ok := destination.(storage.Retainer)
+		if !ok {
+			continue
+		}
+
+		objects, err := retainer.List(ctx)
+		if err != nil {
+			errs[i] = err
+			continue
+		}
+
+		var snapshots []storage.Object
+		for _, object := range objects {
+			if !strings.HasSuffix(object.Key, ChecksumSuffix) {
+				snapshots = append(snapshots, object)
+			}
+		}
+		if len(snapshots) <= d.Config.Retention {
+			continue
+		}
+
+		for _, object := range snapshots[d.Config.Retention:] {
+			if err := retainer.Delete(ctx, object.Key); err != nil {
+				errs[i] = err
+				continue
+			}
+			if err := retainer.Delete(ctx, object.Key+ChecksumSuffix); err != nil {
+				slog.Warn("failed to delete checksum sidecar for pruned snapshot", "destination", destination.Name(), "key", object.Key, "error", err)
+			}
+			slog.Info("pruned old snapshot", "destination", destination.Name(), "key", object.Key)
+		}
+	}
+
+	return joinErrors(errs)
+}
+
+// joinErrors aggregates the non-nil errors in errs into a single error, or
+// nil if there are none.
+func joinErrors(errs []error) error {
+	var messages []string
+	for _, err := range errs {
+		if err != nil {
+			messages = append(messages, err.Error())
+		}
+	}
+	if len(messages) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%d destination(s) failed: %s", len(messages), strings.Join(messages, "; "))
+}
+
+// snapshotName generates a unique snapshot object name for this cycle.
+func (d *Daemon) snapshotName() string {
+	format := d.Config.TimestampFormat
+	if format == "" {
+		format = defaultTimestampFormat
+	}
+	return fmt.Sprintf("vault-raft-%s%s.snap", time.Now().Format(format), d.Config.NameSuffix)
+}
+
+// vaultRaftSnapshot creation
+func vaultRaftSnapshot(client *vault.Client, snapshotPath string) (*os.File, error) {
+	// prepare snaptshot file
+	snapshotFile, err := os.OpenFile(snapshotPath, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		slog.Error("snapshot file could not be created", "path", snapshotPath, "error", err)
+		return nil, err
+	}
+
+	// defer snapshot close
+	defer snapshotFileClose(snapshotFile)
+
+	// execute raft snapshot
+	err = client.Sys().RaftSnapshot(snapshotFile)
+	if err != nil {
+		snapshotFile.Close()
+		slog.Error("Vault Raft snapshot invocation failed", "error", err)
+		return nil, err
+	}
+
+	return snapshotFile, nil
+}
+
+// close snapshot file
+func snapshotFileClose(snapshotFile *os.File) {
+	// close file
+	err := snapshotFile.Close()
+	if err != nil {
+		slog.Error("Vault raft snapshot file failed to close", "error", err)
+	}
+}
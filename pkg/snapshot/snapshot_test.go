@@ -0,0 +1,182 @@
+package snapshot
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/mitodl/vault-raft-backup/pkg/storage"
+)
+
+// fakeDestination is an in-memory storage.SnapshotUploader/storage.Retainer
+// used to exercise retention enforcement without a real backend.
+type fakeDestination struct {
+	objects []storage.Object
+	deleted []string
+}
+
+func (f *fakeDestination) Name() string { return "fake" }
+
+func (f *fakeDestination) Upload(ctx context.Context, name string, r io.Reader) (string, error) {
+	return "", fmt.Errorf("not implemented")
+}
+
+func (f *fakeDestination) List(ctx context.Context) ([]storage.Object, error) {
+	objects := make([]storage.Object, len(f.objects))
+	copy(objects, f.objects)
+	sort.Slice(objects, func(i, j int) bool { return objects[i].LastModified.After(objects[j].LastModified) })
+	return objects, nil
+}
+
+func (f *fakeDestination) Delete(ctx context.Context, key string) error {
+	f.deleted = append(f.deleted, key)
+	return nil
+}
+
+func objectsNewestFirst(n int) []storage.Object {
+	now := time.Unix(1700000000, 0)
+	objects := make([]storage.Object, n)
+	for i := 0; i < n; i++ {
+		objects[i] = storage.Object{
+			Key:          fmt.Sprintf("snapshot-%d", n-i),
+			LastModified: now.Add(-time.Duration(i) * time.Hour),
+		}
+	}
+	return objects
+}
+
+func TestEnforceRetentionDeletesBeyondLimit(t *testing.T) {
+	dest := &fakeDestination{objects: objectsNewestFirst(5)}
+	d := &Daemon{
+		Destinations: []storage.SnapshotUploader{dest},
+		Config:       Config{Retention: 2},
+	}
+
+	if err := d.enforceRetention(context.Background()); err != nil {
+		t.Fatalf("enforceRetention: %v", err)
+	}
+
+	// enforceRetention unconditionally also prunes each snapshot's checksum
+	// sidecar, even when none was listed; a failed sidecar delete is only
+	// logged, and the fake's Delete always succeeds.
+	want := []string{
+		"snapshot-3", "snapshot-3" + ChecksumSuffix,
+		"snapshot-2", "snapshot-2" + ChecksumSuffix,
+		"snapshot-1", "snapshot-1" + ChecksumSuffix,
+	}
+	if len(dest.deleted) != len(want) {
+		t.Fatalf("deleted %v, want %v", dest.deleted, want)
+	}
+	for i, key := range want {
+		if dest.deleted[i] != key {
+			t.Fatalf("deleted[%d] = %q, want %q", i, dest.deleted[i], key)
+		}
+	}
+}
+
+// objectsWithSidecarsNewestFirst mirrors what a real destination actually
+// lists: uploadToAll uploads a .sha256 sidecar for every snapshot through the
+// same Upload path, so List returns two objects per snapshot.
+func objectsWithSidecarsNewestFirst(n int) []storage.Object {
+	now := time.Unix(1700000000, 0)
+	objects := make([]storage.Object, 0, n*2)
+	for i := 0; i < n; i++ {
+		key := fmt.Sprintf("snapshot-%d", n-i)
+		modified := now.Add(-time.Duration(i) * time.Hour)
+		objects = append(objects,
+			storage.Object{Key: key, LastModified: modified},
+			storage.Object{Key: key + ChecksumSuffix, LastModified: modified},
+		)
+	}
+	return objects
+}
+
+func TestEnforceRetentionIgnoresChecksumSidecars(t *testing.T) {
+	dest := &fakeDestination{objects: objectsWithSidecarsNewestFirst(5)}
+	d := &Daemon{
+		Destinations: []storage.SnapshotUploader{dest},
+		Config:       Config{Retention: 2},
+	}
+
+	if err := d.enforceRetention(context.Background()); err != nil {
+		t.Fatalf("enforceRetention: %v", err)
+	}
+
+	want := map[string]bool{
+		"snapshot-3": true, "snapshot-3" + ChecksumSuffix: true,
+		"snapshot-2": true, "snapshot-2" + ChecksumSuffix: true,
+		"snapshot-1": true, "snapshot-1" + ChecksumSuffix: true,
+	}
+	if len(dest.deleted) != len(want) {
+		t.Fatalf("deleted %v, want keys %v", dest.deleted, want)
+	}
+	for _, key := range dest.deleted {
+		if !want[key] {
+			t.Fatalf("unexpectedly deleted %q", key)
+		}
+	}
+	for _, kept := range []string{"snapshot-5", "snapshot-5" + ChecksumSuffix, "snapshot-4", "snapshot-4" + ChecksumSuffix} {
+		for _, deleted := range dest.deleted {
+			if deleted == kept {
+				t.Fatalf("retained snapshot %q was deleted", kept)
+			}
+		}
+	}
+}
+
+func TestEnforceRetentionNoopUnderLimit(t *testing.T) {
+	dest := &fakeDestination{objects: objectsNewestFirst(2)}
+	d := &Daemon{
+		Destinations: []storage.SnapshotUploader{dest},
+		Config:       Config{Retention: 5},
+	}
+
+	if err := d.enforceRetention(context.Background()); err != nil {
+		t.Fatalf("enforceRetention: %v", err)
+	}
+	if len(dest.deleted) != 0 {
+		t.Fatalf("deleted %v, want none", dest.deleted)
+	}
+}
+
+func TestEnforceRetentionSkipsNonRetainers(t *testing.T) {
+	dest := &nonRetainerDestination{}
+	d := &Daemon{
+		Destinations: []storage.SnapshotUploader{dest},
+		Config:       Config{Retention: 1},
+	}
+
+	if err := d.enforceRetention(context.Background()); err != nil {
+		t.Fatalf("enforceRetention: %v", err)
+	}
+}
+
+// nonRetainerDestination implements storage.SnapshotUploader but not
+// storage.Retainer, matching destinations that can't enumerate their objects.
+type nonRetainerDestination struct{}
+
+func (nonRetainerDestination) Name() string { return "non-retainer" }
+func (nonRetainerDestination) Upload(ctx context.Context, name string, r io.Reader) (string, error) {
+	return "", fmt.Errorf("not implemented")
+}
+
+func TestJoinErrorsNilOnNoErrors(t *testing.T) {
+	if err := joinErrors(make([]error, 3)); err != nil {
+		t.Fatalf("joinErrors(no errors) = %v, want nil", err)
+	}
+}
+
+func TestJoinErrorsAggregatesMessages(t *testing.T) {
+	errs := []error{fmt.Errorf("a"), nil, fmt.Errorf("b")}
+	err := joinErrors(errs)
+	if err == nil {
+		t.Fatal("joinErrors(errs) = nil, want non-nil")
+	}
+	want := "2 destination(s) failed: a; b"
+	if err.Error() != want {
+		t.Fatalf("joinErrors(errs) = %q, want %q", err.Error(), want)
+	}
+}
@@ -0,0 +1,107 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"github.com/Azure/azure-sdk-for-go/sdk/storage/azblob"
+)
+
+// AzureConfig configures an AzureUploader.
+type AzureConfig struct {
+	Account   string
+	Container string
+	Prefix    string
+	// AccountKey authenticates with a shared key. When empty, the uploader
+	// falls back to Azure's default credential chain (managed identity, CLI, etc).
+	AccountKey string
+}
+
+// AzureUploader uploads snapshots to Azure Blob Storage.
+type AzureUploader struct {
+	config AzureConfig
+	client *azblob.Client
+}
+
+// NewAzureUploader builds an AzureUploader for config.Account/config.Container.
+func NewAzureUploader(config AzureConfig) (*AzureUploader, error) {
+	serviceURL := fmt.Sprintf("https://%s.blob.core.windows.net/", config.Account)
+
+	var client *azblob.Client
+	if config.AccountKey != "" {
+		credential, err := azblob.NewSharedKeyCredential(config.Account, config.AccountKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize Azure shared key credential: %w", err)
+		}
+		client, err = azblob.NewClientWithSharedKeyCredential(serviceURL, credential, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize Azure Blob client: %w", err)
+		}
+	} else {
+		credential, err := azidentity.NewDefaultAzureCredential(nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize Azure default credential: %w", err)
+		}
+		client, err = azblob.NewClient(serviceURL, credential, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to initialize Azure Blob client: %w", err)
+		}
+	}
+
+	return &AzureUploader{config: config, client: client}, nil
+}
+
+// Name implements SnapshotUploader.
+func (u *AzureUploader) Name() string { return "azure" }
+
+// Upload implements SnapshotUploader.
+func (u *AzureUploader) Upload(ctx context.Context, name string, r io.Reader) (string, error) {
+	key := filepath.Join(u.config.Prefix, name)
+
+	// azblob needs a ReadSeekCloser; buffer the snapshot since it's already
+	// streamed once through the compression/encryption pipeline upstream.
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return "", fmt.Errorf("failed to read snapshot for Azure upload: %w", err)
+	}
+
+	_, err = u.client.UploadBuffer(ctx, u.config.Container, key, data, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to upload snapshot to Azure container %s: %w", u.config.Container, err)
+	}
+
+	return fmt.Sprintf("https://%s.blob.core.windows.net/%s/%s", u.config.Account, u.config.Container, key), nil
+}
+
+// List implements Retainer.
+func (u *AzureUploader) List(ctx context.Context) ([]Object, error) {
+	var objects []Object
+	pager := u.client.NewListBlobsFlatPager(u.config.Container, &azblob.ListBlobsFlatOptions{
+		Prefix: &u.config.Prefix,
+	})
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("failed to list snapshots under %s: %w", u.config.Prefix, err)
+		}
+		for _, blob := range page.Segment.BlobItems {
+			objects = append(objects, Object{Key: *blob.Name, LastModified: *blob.Properties.LastModified})
+		}
+	}
+
+	sort.Slice(objects, func(i, j int) bool { return objects[i].LastModified.After(objects[j].LastModified) })
+	return objects, nil
+}
+
+// Delete implements Retainer.
+func (u *AzureUploader) Delete(ctx context.Context, key string) error {
+	_, err := u.client.DeleteBlob(ctx, u.config.Container, key, nil)
+	if err != nil {
+		return fmt.Errorf("failed to delete snapshot %s: %w", key, err)
+	}
+	return nil
+}
@@ -0,0 +1,77 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// LoadFromEnv builds the ordered list of enabled destinations named by
+// SNAPSHOT_DESTINATIONS (a comma-separated list, e.g. "s3,local"). Each
+// destination reads its own configuration from env vars below. Defaults to
+// a single S3 destination using S3_BUCKET/S3_PREFIX/AWS_REGION when
+// SNAPSHOT_DESTINATIONS is unset, to match the tool's original behavior.
+func LoadFromEnv(ctx context.Context) ([]SnapshotUploader, error) {
+	names := os.Getenv("SNAPSHOT_DESTINATIONS")
+	if names == "" {
+		names = "s3"
+	}
+
+	var uploaders []SnapshotUploader
+	for _, name := range strings.Split(names, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		uploader, err := newUploader(ctx, name)
+		if err != nil {
+			return nil, fmt.Errorf("failed to configure %q destination: %w", name, err)
+		}
+		uploaders = append(uploaders, uploader)
+	}
+
+	return uploaders, nil
+}
+
+func newUploader(ctx context.Context, name string) (SnapshotUploader, error) {
+	switch name {
+	case "s3":
+		return NewS3Uploader(S3Config{
+			Bucket: os.Getenv("S3_BUCKET"),
+			Prefix: os.Getenv("S3_PREFIX"),
+			Region: os.Getenv("AWS_REGION"),
+		})
+	case "minio":
+		pathStyle, _ := strconv.ParseBool(os.Getenv("MINIO_FORCE_PATH_STYLE"))
+		return NewS3Uploader(S3Config{
+			Bucket:         os.Getenv("MINIO_BUCKET"),
+			Prefix:         os.Getenv("MINIO_PREFIX"),
+			Region:         os.Getenv("MINIO_REGION"),
+			Endpoint:       os.Getenv("MINIO_ENDPOINT"),
+			ForcePathStyle: pathStyle,
+			AccessKey:      os.Getenv("MINIO_ACCESS_KEY"),
+			SecretKey:      os.Getenv("MINIO_SECRET_KEY"),
+		})
+	case "gcs":
+		return NewGCSUploader(ctx, GCSConfig{
+			Bucket: os.Getenv("GCS_BUCKET"),
+			Prefix: os.Getenv("GCS_PREFIX"),
+		})
+	case "azure":
+		return NewAzureUploader(AzureConfig{
+			Account:    os.Getenv("AZURE_STORAGE_ACCOUNT"),
+			Container:  os.Getenv("AZURE_STORAGE_CONTAINER"),
+			Prefix:     os.Getenv("AZURE_PREFIX"),
+			AccountKey: os.Getenv("AZURE_STORAGE_KEY"),
+		})
+	case "local":
+		return NewLocalUploader(LocalConfig{
+			Dir: os.Getenv("LOCAL_BACKUP_DIR"),
+		})
+	default:
+		return nil, fmt.Errorf("unknown snapshot destination %q", name)
+	}
+}
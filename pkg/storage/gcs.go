@@ -0,0 +1,80 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+
+	"cloud.google.com/go/storage"
+	"google.golang.org/api/iterator"
+)
+
+// GCSConfig configures a GCSUploader.
+type GCSConfig struct {
+	Bucket string
+	Prefix string
+}
+
+// GCSUploader uploads snapshots to Google Cloud Storage.
+type GCSUploader struct {
+	config GCSConfig
+	client *storage.Client
+}
+
+// NewGCSUploader builds a GCSUploader, authenticating via the default
+// application credentials (e.g. GOOGLE_APPLICATION_CREDENTIALS).
+func NewGCSUploader(ctx context.Context, config GCSConfig) (*GCSUploader, error) {
+	client, err := storage.NewClient(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize GCS client: %w", err)
+	}
+	return &GCSUploader{config: config, client: client}, nil
+}
+
+// Name implements SnapshotUploader.
+func (u *GCSUploader) Name() string { return "gcs" }
+
+// Upload implements SnapshotUploader.
+func (u *GCSUploader) Upload(ctx context.Context, name string, r io.Reader) (string, error) {
+	key := filepath.Join(u.config.Prefix, name)
+	writer := u.client.Bucket(u.config.Bucket).Object(key).NewWriter(ctx)
+
+	if _, err := io.Copy(writer, r); err != nil {
+		writer.Close()
+		return "", fmt.Errorf("failed to upload snapshot to GCS bucket %s: %w", u.config.Bucket, err)
+	}
+	if err := writer.Close(); err != nil {
+		return "", fmt.Errorf("failed to finalize upload to GCS bucket %s: %w", u.config.Bucket, err)
+	}
+
+	return fmt.Sprintf("gs://%s/%s", u.config.Bucket, key), nil
+}
+
+// List implements Retainer.
+func (u *GCSUploader) List(ctx context.Context) ([]Object, error) {
+	var objects []Object
+	it := u.client.Bucket(u.config.Bucket).Objects(ctx, &storage.Query{Prefix: u.config.Prefix})
+	for {
+		attrs, err := it.Next()
+		if err == iterator.Done {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to list snapshots under %s: %w", u.config.Prefix, err)
+		}
+		objects = append(objects, Object{Key: attrs.Name, LastModified: attrs.Updated})
+	}
+
+	sort.Slice(objects, func(i, j int) bool { return objects[i].LastModified.After(objects[j].LastModified) })
+	return objects, nil
+}
+
+// Delete implements Retainer.
+func (u *GCSUploader) Delete(ctx context.Context, key string) error {
+	if err := u.client.Bucket(u.config.Bucket).Object(key).Delete(ctx); err != nil {
+		return fmt.Errorf("failed to delete snapshot %s: %w", key, err)
+	}
+	return nil
+}
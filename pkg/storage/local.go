@@ -0,0 +1,81 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// LocalConfig configures a LocalUploader.
+type LocalConfig struct {
+	Dir string
+}
+
+// LocalUploader writes snapshots to a directory on the local filesystem, for
+// air-gapped environments or testing.
+type LocalUploader struct {
+	config LocalConfig
+}
+
+// NewLocalUploader builds a LocalUploader, creating config.Dir if needed.
+func NewLocalUploader(config LocalConfig) (*LocalUploader, error) {
+	if err := os.MkdirAll(config.Dir, 0o755); err != nil {
+		return nil, fmt.Errorf("failed to create local backup directory %s: %w", config.Dir, err)
+	}
+	return &LocalUploader{config: config}, nil
+}
+
+// Name implements SnapshotUploader.
+func (u *LocalUploader) Name() string { return "local" }
+
+// Upload implements SnapshotUploader.
+func (u *LocalUploader) Upload(ctx context.Context, name string, r io.Reader) (string, error) {
+	path := filepath.Join(u.config.Dir, name)
+
+	file, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, 0o644)
+	if err != nil {
+		return "", fmt.Errorf("failed to create local snapshot file %s: %w", path, err)
+	}
+	defer file.Close()
+
+	if _, err := io.Copy(file, r); err != nil {
+		return "", fmt.Errorf("failed to write local snapshot file %s: %w", path, err)
+	}
+
+	return path, nil
+}
+
+// List implements Retainer.
+func (u *LocalUploader) List(ctx context.Context) ([]Object, error) {
+	entries, err := os.ReadDir(u.config.Dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list local backup directory %s: %w", u.config.Dir, err)
+	}
+
+	var objects []Object
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, fmt.Errorf("failed to stat local snapshot %s: %w", entry.Name(), err)
+		}
+		objects = append(objects, Object{Key: entry.Name(), LastModified: info.ModTime()})
+	}
+
+	sort.Slice(objects, func(i, j int) bool { return objects[i].LastModified.After(objects[j].LastModified) })
+	return objects, nil
+}
+
+// Delete implements Retainer.
+func (u *LocalUploader) Delete(ctx context.Context, key string) error {
+	path := filepath.Join(u.config.Dir, key)
+	if err := os.Remove(path); err != nil {
+		return fmt.Errorf("failed to delete local snapshot %s: %w", path, err)
+	}
+	return nil
+}
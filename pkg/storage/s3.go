@@ -0,0 +1,110 @@
+package storage
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"path/filepath"
+	"sort"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+	"github.com/aws/aws-sdk-go/service/s3/s3manager"
+)
+
+// S3Config configures an S3Uploader. Endpoint, ForcePathStyle, AccessKey and
+// SecretKey are only needed for S3-compatible services such as MinIO; when
+// Endpoint is empty the uploader talks to AWS S3 using the default
+// credential chain.
+type S3Config struct {
+	Bucket         string
+	Prefix         string
+	Region         string
+	Endpoint       string
+	ForcePathStyle bool
+	AccessKey      string
+	SecretKey      string
+}
+
+// S3Uploader uploads snapshots to AWS S3 or an S3-compatible endpoint (e.g. MinIO).
+type S3Uploader struct {
+	config S3Config
+	client *s3.S3
+}
+
+// NewS3Uploader builds an S3Uploader from config.
+func NewS3Uploader(config S3Config) (*S3Uploader, error) {
+	awsConfig := &aws.Config{Region: aws.String(config.Region)}
+	if config.Endpoint != "" {
+		awsConfig.Endpoint = aws.String(config.Endpoint)
+		awsConfig.S3ForcePathStyle = aws.Bool(config.ForcePathStyle)
+	}
+	if config.AccessKey != "" || config.SecretKey != "" {
+		awsConfig.Credentials = credentials.NewStaticCredentials(config.AccessKey, config.SecretKey, "")
+	}
+
+	awsSession, err := session.NewSession(awsConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to initialize S3 session: %w", err)
+	}
+
+	return &S3Uploader{config: config, client: s3.New(awsSession)}, nil
+}
+
+// Name implements SnapshotUploader.
+func (u *S3Uploader) Name() string {
+	if u.config.Endpoint != "" {
+		return "minio"
+	}
+	return "s3"
+}
+
+// Upload implements SnapshotUploader.
+func (u *S3Uploader) Upload(ctx context.Context, name string, r io.Reader) (string, error) {
+	uploader := s3manager.NewUploaderWithClient(u.client)
+
+	result, err := uploader.UploadWithContext(ctx, &s3manager.UploadInput{
+		Bucket: aws.String(u.config.Bucket),
+		Key:    aws.String(filepath.Join(u.config.Prefix, name)),
+		Body:   r,
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to upload snapshot to S3 bucket %s: %w", u.config.Bucket, err)
+	}
+
+	return result.Location, nil
+}
+
+// List implements Retainer.
+func (u *S3Uploader) List(ctx context.Context) ([]Object, error) {
+	var objects []Object
+	err := u.client.ListObjectsV2PagesWithContext(ctx, &s3.ListObjectsV2Input{
+		Bucket: aws.String(u.config.Bucket),
+		Prefix: aws.String(u.config.Prefix),
+	}, func(page *s3.ListObjectsV2Output, lastPage bool) bool {
+		for _, object := range page.Contents {
+			objects = append(objects, Object{Key: aws.StringValue(object.Key), LastModified: *object.LastModified})
+		}
+		return true
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to list snapshots under %s: %w", u.config.Prefix, err)
+	}
+
+	sort.Slice(objects, func(i, j int) bool { return objects[i].LastModified.After(objects[j].LastModified) })
+	return objects, nil
+}
+
+// Delete implements Retainer.
+func (u *S3Uploader) Delete(ctx context.Context, key string) error {
+	_, err := u.client.DeleteObjectWithContext(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(u.config.Bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete snapshot %s: %w", key, err)
+	}
+	return nil
+}
@@ -0,0 +1,33 @@
+// Package storage provides pluggable snapshot destinations. A snapshot cycle
+// fans the same snapshot out to every configured SnapshotUploader so the tool
+// is not locked to a single cloud provider.
+package storage
+
+import (
+	"context"
+	"io"
+	"time"
+)
+
+// SnapshotUploader uploads a named snapshot to a storage destination and
+// returns a human-readable location for it (e.g. "gs://bucket/key").
+type SnapshotUploader interface {
+	// Name identifies the destination type for metrics and logging, e.g. "s3".
+	Name() string
+	Upload(ctx context.Context, name string, r io.Reader) (location string, err error)
+}
+
+// Object describes a previously uploaded snapshot.
+type Object struct {
+	Key          string
+	LastModified time.Time
+}
+
+// Retainer is implemented by destinations that can enumerate and delete
+// previously uploaded snapshots. The snapshot daemon uses it to enforce
+// retention; destinations that don't implement it are simply skipped during
+// retention cleanup.
+type Retainer interface {
+	List(ctx context.Context) ([]Object, error)
+	Delete(ctx context.Context, key string) error
+}
@@ -0,0 +1,42 @@
+package vaultauth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	vault "github.com/hashicorp/vault/api"
+	approleauth "github.com/hashicorp/vault/api/auth/approle"
+)
+
+// AppRoleAuth authenticates using the AppRole auth method. SecretID may come
+// from a literal value or a file on disk (e.g. a Kubernetes-mounted secret).
+type AppRoleAuth struct {
+	RoleID       string
+	SecretID     string
+	SecretIDFile string
+}
+
+// Login implements AuthMethod.
+func (a AppRoleAuth) Login(ctx context.Context, client *vault.Client) (*vault.Secret, error) {
+	var secretID approleauth.SecretID
+	switch {
+	case a.SecretIDFile != "":
+		secretID = approleauth.SecretID{FromFile: a.SecretIDFile}
+	case a.SecretID != "":
+		secretID = approleauth.SecretID{FromString: a.SecretID}
+	default:
+		return nil, errors.New("either VAULT_APPROLE_SECRET_ID or VAULT_APPROLE_SECRET_ID_FILE is required")
+	}
+
+	method, err := approleauth.NewAppRoleAuth(a.RoleID, &secretID)
+	if err != nil {
+		return nil, fmt.Errorf("unable to initialize AppRole authentication: %w", err)
+	}
+
+	secret, err := client.Auth().Login(ctx, method)
+	if err != nil {
+		return nil, fmt.Errorf("unable to login to AppRole auth method: %w", err)
+	}
+	return secret, nil
+}
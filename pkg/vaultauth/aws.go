@@ -0,0 +1,31 @@
+package vaultauth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	vault "github.com/hashicorp/vault/api"
+	awsauth "github.com/hashicorp/vault/api/auth/aws"
+)
+
+// AWSAuth authenticates using the AWS IAM auth method.
+type AWSAuth struct{}
+
+// Login implements AuthMethod.
+func (AWSAuth) Login(ctx context.Context, client *vault.Client) (*vault.Secret, error) {
+	method, err := awsauth.NewAWSAuth(awsauth.WithIAMAuth())
+	if err != nil {
+		return nil, fmt.Errorf("unable to initialize AWS IAM authentication: %w", err)
+	}
+
+	secret, err := client.Auth().Login(ctx, method)
+	if err != nil {
+		return nil, fmt.Errorf("unable to login to AWS IAM auth method: %w", err)
+	}
+	if secret == nil {
+		return nil, errors.New("no auth info was returned after AWS IAM login")
+	}
+
+	return secret, nil
+}
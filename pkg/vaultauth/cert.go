@@ -0,0 +1,32 @@
+package vaultauth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	vault "github.com/hashicorp/vault/api"
+)
+
+// CertAuth authenticates using the cert (mTLS) auth method. The client
+// certificate itself is configured on the vault.Client's TLS config; this
+// just invokes the login endpoint for the named certificate role.
+type CertAuth struct {
+	Name string
+}
+
+// Login implements AuthMethod.
+func (a CertAuth) Login(ctx context.Context, client *vault.Client) (*vault.Secret, error) {
+	secret, err := client.Logical().Write("auth/cert/login", map[string]interface{}{
+		"name": a.Name,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to login to cert auth method: %w", err)
+	}
+	if secret == nil || secret.Auth == nil {
+		return nil, errors.New("no auth info was returned after cert login")
+	}
+
+	client.SetToken(secret.Auth.ClientToken)
+	return secret, nil
+}
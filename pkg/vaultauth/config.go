@@ -0,0 +1,46 @@
+package vaultauth
+
+import (
+	"fmt"
+	"os"
+)
+
+// LoadFromEnv builds the AuthMethod selected by VAULT_AUTH_METHOD. It
+// defaults to "token" using VAULT_TOKEN, preserving the tool's original
+// behavior of treating the literal value "aws-iam" there as a request for
+// AWS IAM auth instead of a static token.
+func LoadFromEnv() (AuthMethod, error) {
+	switch method := os.Getenv("VAULT_AUTH_METHOD"); method {
+	case "", "token":
+		if os.Getenv("VAULT_TOKEN") == "aws-iam" {
+			return AWSAuth{}, nil
+		}
+		return TokenAuth{Token: os.Getenv("VAULT_TOKEN")}, nil
+	case "aws-iam":
+		return AWSAuth{}, nil
+	case "approle":
+		return AppRoleAuth{
+			RoleID:       os.Getenv("VAULT_APPROLE_ROLE_ID"),
+			SecretID:     os.Getenv("VAULT_APPROLE_SECRET_ID"),
+			SecretIDFile: os.Getenv("VAULT_APPROLE_SECRET_ID_FILE"),
+		}, nil
+	case "kubernetes":
+		return KubernetesAuth{Role: os.Getenv("VAULT_KUBERNETES_ROLE")}, nil
+	case "jwt", "oidc":
+		return JWTAuth{
+			Mount:   os.Getenv("VAULT_JWT_MOUNT"),
+			Role:    os.Getenv("VAULT_JWT_ROLE"),
+			JWT:     os.Getenv("VAULT_JWT"),
+			JWTFile: os.Getenv("VAULT_JWT_FILE"),
+		}, nil
+	case "cert":
+		return CertAuth{Name: os.Getenv("VAULT_CERT_NAME")}, nil
+	case "userpass":
+		return UserpassAuth{
+			Username: os.Getenv("VAULT_USERPASS_USERNAME"),
+			Password: os.Getenv("VAULT_USERPASS_PASSWORD"),
+		}, nil
+	default:
+		return nil, fmt.Errorf("unknown VAULT_AUTH_METHOD %q", method)
+	}
+}
@@ -0,0 +1,56 @@
+package vaultauth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	vault "github.com/hashicorp/vault/api"
+)
+
+// JWTAuth authenticates using the jwt (or a statically-issued oidc) auth
+// method by writing directly to auth/<mount>/login, since there is no
+// interactive browser flow available to a headless backup daemon.
+type JWTAuth struct {
+	Mount string
+	Role  string
+	JWT   string
+	// JWTFile, if set, is read for the JWT instead of JWT (e.g. a
+	// Kubernetes/CI-injected token file).
+	JWTFile string
+}
+
+// Login implements AuthMethod.
+func (a JWTAuth) Login(ctx context.Context, client *vault.Client) (*vault.Secret, error) {
+	jwt := a.JWT
+	if a.JWTFile != "" {
+		data, err := os.ReadFile(a.JWTFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read JWT file %s: %w", a.JWTFile, err)
+		}
+		jwt = string(data)
+	}
+	if jwt == "" {
+		return nil, errors.New("a JWT is required for the jwt/oidc auth method (VAULT_JWT or VAULT_JWT_FILE)")
+	}
+
+	mount := a.Mount
+	if mount == "" {
+		mount = "jwt"
+	}
+
+	secret, err := client.Logical().Write(fmt.Sprintf("auth/%s/login", mount), map[string]interface{}{
+		"role": a.Role,
+		"jwt":  jwt,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("unable to login to %s auth method: %w", mount, err)
+	}
+	if secret == nil || secret.Auth == nil {
+		return nil, fmt.Errorf("no auth info was returned after %s login", mount)
+	}
+
+	client.SetToken(secret.Auth.ClientToken)
+	return secret, nil
+}
@@ -0,0 +1,29 @@
+package vaultauth
+
+import (
+	"context"
+	"fmt"
+
+	vault "github.com/hashicorp/vault/api"
+	k8sauth "github.com/hashicorp/vault/api/auth/kubernetes"
+)
+
+// KubernetesAuth authenticates using the Kubernetes auth method, presenting
+// the pod's projected service account JWT.
+type KubernetesAuth struct {
+	Role string
+}
+
+// Login implements AuthMethod.
+func (a KubernetesAuth) Login(ctx context.Context, client *vault.Client) (*vault.Secret, error) {
+	method, err := k8sauth.NewKubernetesAuth(a.Role)
+	if err != nil {
+		return nil, fmt.Errorf("unable to initialize Kubernetes authentication: %w", err)
+	}
+
+	secret, err := client.Auth().Login(ctx, method)
+	if err != nil {
+		return nil, fmt.Errorf("unable to login to Kubernetes auth method: %w", err)
+	}
+	return secret, nil
+}
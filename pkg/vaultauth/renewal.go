@@ -0,0 +1,74 @@
+package vaultauth
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"time"
+
+	vault "github.com/hashicorp/vault/api"
+)
+
+// StartRenewal watches the lease behind authInfo and keeps client authenticated
+// for the life of ctx: renewing the token before expiry when the lease is
+// renewable, or performing a full re-login via method otherwise.
+func StartRenewal(ctx context.Context, client *vault.Client, method AuthMethod, authInfo *vault.Secret) {
+	go renewalLoop(ctx, client, method, authInfo)
+}
+
+func renewalLoop(ctx context.Context, client *vault.Client, method AuthMethod, authInfo *vault.Secret) {
+	for {
+		leaseDuration, renewable := leaseInfo(authInfo)
+		if leaseDuration <= 0 {
+			leaseDuration = time.Hour
+		}
+
+		// Renew (or re-login) at 2/3 of the lease duration, matching Vault's
+		// own recommended renewal cadence.
+		select {
+		case <-ctx.Done():
+			return
+		case <-time.After(leaseDuration * 2 / 3):
+		}
+
+		if renewable {
+			secret, err := client.Auth().Token().RenewSelf(0)
+			if err == nil {
+				authInfo = secret
+				continue
+			}
+			slog.Error("Vault token renewal failed, falling back to full re-login", "error", err)
+		}
+
+		secret, err := method.Login(ctx, client)
+		if err != nil {
+			slog.Error("Vault re-login failed", "error", err)
+			continue
+		}
+		authInfo = secret
+	}
+}
+
+// leaseInfo extracts the lease duration and renewability from a secret
+// returned by either AuthMethod.Login (an Auth block) or LookupSelf (a Data
+// block, for a pre-issued static token).
+func leaseInfo(secret *vault.Secret) (time.Duration, bool) {
+	if secret == nil {
+		return 0, false
+	}
+	if secret.Auth != nil {
+		return time.Duration(secret.Auth.LeaseDuration) * time.Second, secret.Auth.Renewable
+	}
+	if secret.Data != nil {
+		renewable, _ := secret.Data["renewable"].(bool)
+		switch ttl := secret.Data["ttl"].(type) {
+		case float64:
+			return time.Duration(ttl) * time.Second, renewable
+		case json.Number:
+			if seconds, err := ttl.Int64(); err == nil {
+				return time.Duration(seconds) * time.Second, renewable
+			}
+		}
+	}
+	return 0, false
+}
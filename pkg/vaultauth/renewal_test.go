@@ -0,0 +1,52 @@
+package vaultauth
+
+import (
+	"testing"
+	"time"
+
+	vault "github.com/hashicorp/vault/api"
+)
+
+func TestLeaseInfoNilSecret(t *testing.T) {
+	duration, renewable := leaseInfo(nil)
+	if duration != 0 || renewable {
+		t.Fatalf("got (%v, %v), want (0, false)", duration, renewable)
+	}
+}
+
+func TestLeaseInfoFromAuth(t *testing.T) {
+	secret := &vault.Secret{
+		Auth: &vault.SecretAuth{
+			LeaseDuration: 3600,
+			Renewable:     true,
+		},
+	}
+
+	duration, renewable := leaseInfo(secret)
+	if duration != time.Hour || !renewable {
+		t.Fatalf("got (%v, %v), want (1h0m0s, true)", duration, renewable)
+	}
+}
+
+func TestLeaseInfoFromData(t *testing.T) {
+	secret := &vault.Secret{
+		Data: map[string]interface{}{
+			"ttl":       float64(1800),
+			"renewable": false,
+		},
+	}
+
+	duration, renewable := leaseInfo(secret)
+	if duration != 30*time.Minute || renewable {
+		t.Fatalf("got (%v, %v), want (30m0s, false)", duration, renewable)
+	}
+}
+
+func TestLeaseInfoMissingData(t *testing.T) {
+	secret := &vault.Secret{Data: map[string]interface{}{}}
+
+	duration, renewable := leaseInfo(secret)
+	if duration != 0 || renewable {
+		t.Fatalf("got (%v, %v), want (0, false)", duration, renewable)
+	}
+}
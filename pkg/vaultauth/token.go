@@ -0,0 +1,32 @@
+package vaultauth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	vault "github.com/hashicorp/vault/api"
+)
+
+// TokenAuth authenticates with a pre-issued Vault token. Unlike the tool's
+// original 26-character length heuristic, validity is checked by actually
+// asking Vault about the token via LookupSelf.
+type TokenAuth struct {
+	Token string
+}
+
+// Login implements AuthMethod.
+func (a TokenAuth) Login(ctx context.Context, client *vault.Client) (*vault.Secret, error) {
+	if a.Token == "" {
+		return nil, errors.New("VAULT_TOKEN is required for the token auth method")
+	}
+
+	client.SetToken(a.Token)
+
+	secret, err := client.Auth().Token().LookupSelf()
+	if err != nil {
+		return nil, fmt.Errorf("the Vault token is invalid: %w", err)
+	}
+
+	return secret, nil
+}
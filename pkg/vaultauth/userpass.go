@@ -0,0 +1,29 @@
+package vaultauth
+
+import (
+	"context"
+	"fmt"
+
+	vault "github.com/hashicorp/vault/api"
+	userpassauth "github.com/hashicorp/vault/api/auth/userpass"
+)
+
+// UserpassAuth authenticates using the userpass auth method.
+type UserpassAuth struct {
+	Username string
+	Password string
+}
+
+// Login implements AuthMethod.
+func (a UserpassAuth) Login(ctx context.Context, client *vault.Client) (*vault.Secret, error) {
+	method, err := userpassauth.NewUserpassAuth(a.Username, &userpassauth.Password{FromString: a.Password})
+	if err != nil {
+		return nil, fmt.Errorf("unable to initialize userpass authentication: %w", err)
+	}
+
+	secret, err := client.Auth().Login(ctx, method)
+	if err != nil {
+		return nil, fmt.Errorf("unable to login to userpass auth method: %w", err)
+	}
+	return secret, nil
+}
@@ -0,0 +1,14 @@
+// Package vaultauth implements pluggable Vault authentication methods and
+// keeps the resulting token renewed for the life of the process.
+package vaultauth
+
+import (
+	"context"
+
+	vault "github.com/hashicorp/vault/api"
+)
+
+// AuthMethod logs in to Vault and returns the resulting auth secret.
+type AuthMethod interface {
+	Login(ctx context.Context, client *vault.Client) (*vault.Secret, error)
+}